@@ -0,0 +1,11 @@
+//go:build embedded_sqlite
+
+package cluster
+
+// Importing this package registers the embedded sqlite managed driver with the managed
+// package's driver registry. It is only compiled in when built with the embedded_sqlite
+// build tag, since it is not needed by downstreams that only ever use etcd or an external
+// datastore.
+import (
+	_ "github.com/rancher/k3s/pkg/cluster/managed/sqlite"
+)
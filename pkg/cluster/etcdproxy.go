@@ -0,0 +1,99 @@
+package cluster
+
+import (
+	"context"
+	"time"
+
+	"github.com/rancher/k3s/pkg/etcd"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	etcdProxyResyncInterval = 5 * time.Minute
+	etcdProxyPollInterval   = 30 * time.Second
+	etcdProxyInitialBackoff = time.Second
+	etcdProxyMaxBackoff     = 30 * time.Second
+)
+
+// setupEtcdProxy keeps etcdProxy's endpoint list in sync with the managed datastore's
+// membership. It prefers the event-driven managed.Driver.WatchMembers channel, falling back to
+// polling GetMembersClientURLs on etcdProxyPollInterval whenever a watch can't be established.
+// A watch that is lost is reestablished after an exponential backoff, which is reset once a
+// watch has lasted until the next resync. A full resync is forced periodically regardless, as a
+// safety net in case a watch silently misses an update.
+func (c *Cluster) setupEtcdProxy(ctx context.Context, etcdProxy etcd.Proxy) {
+	if c.managedDB == nil {
+		return
+	}
+	go c.runEtcdProxySync(ctx, etcdProxy)
+}
+
+func (c *Cluster) runEtcdProxySync(ctx context.Context, etcdProxy etcd.Proxy) {
+	resync := time.NewTicker(etcdProxyResyncInterval)
+	defer resync.Stop()
+
+	backoff := etcdProxyInitialBackoff
+	for {
+		if addresses, err := c.managedDB.GetMembersClientURLs(ctx); err != nil {
+			logrus.Warnf("failed to get etcd client URLs: %v", err)
+		} else {
+			etcdProxy.Update(addresses)
+		}
+
+		watchCtx, cancelWatch := context.WithCancel(ctx)
+		watch, err := c.managedDB.WatchMembers(watchCtx)
+		if err != nil {
+			cancelWatch()
+			logrus.Warnf("failed to watch etcd membership, falling back to polling: %v", err)
+			select {
+			case <-time.After(etcdProxyPollInterval):
+			case <-ctx.Done():
+				return
+			}
+			continue
+		}
+
+		cont := c.consumeEtcdProxyWatch(ctx, etcdProxy, watch, resync.C, &backoff)
+		// Tear down this watch before reconnecting or resyncing, whether or not we're about to
+		// loop again, so that a resync doesn't leak the previous watch's channel/goroutine.
+		cancelWatch()
+		if !cont {
+			return
+		}
+	}
+}
+
+// consumeEtcdProxyWatch applies updates from watch to etcdProxy until the watch closes, the
+// resync ticker fires, or ctx is cancelled. It returns false if the caller should stop
+// entirely, and true if it should reestablish the watch and loop again.
+func (c *Cluster) consumeEtcdProxyWatch(ctx context.Context, etcdProxy etcd.Proxy, watch <-chan []string, resync <-chan time.Time, backoff *time.Duration) bool {
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-resync:
+			// This watch lasted until the resync, so the reconnect backoff starts over.
+			*backoff = etcdProxyInitialBackoff
+			return true
+		case addresses, ok := <-watch:
+			if !ok {
+				logrus.Warnf("etcd membership watch closed, reconnecting in %s", *backoff)
+				select {
+				case <-time.After(*backoff):
+				case <-ctx.Done():
+					return false
+				}
+				*backoff = minDuration(*backoff*2, etcdProxyMaxBackoff)
+				return true
+			}
+			etcdProxy.Update(addresses)
+		}
+	}
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,59 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/rancher/k3s/pkg/cluster/managed"
+	"github.com/sirupsen/logrus"
+)
+
+// healthCacheTTL is how stale the cache kept by refreshHealthCache may be before writeHealth
+// falls back to a live call to the driver rather than trusting it.
+const healthCacheTTL = 15 * time.Second
+
+// healthHandler serves the /db/health, /db/members, and /db/leader HTTP endpoints from the
+// managed driver's Health snapshot, falling through to next for any other path.
+func (c *Cluster) healthHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if c.managedDB == nil {
+			next.ServeHTTP(rw, req)
+			return
+		}
+
+		switch req.URL.Path {
+		case "/db/health":
+			c.writeHealth(req.Context(), rw, func(s managed.HealthStatus) interface{} { return s })
+		case "/db/members":
+			c.writeHealth(req.Context(), rw, func(s managed.HealthStatus) interface{} { return s.Members })
+		case "/db/leader":
+			c.writeHealth(req.Context(), rw, func(s managed.HealthStatus) interface{} { return map[string]string{"leader": s.Leader} })
+		default:
+			next.ServeHTTP(rw, req)
+		}
+	})
+}
+
+// writeHealth serves project(status) as JSON, preferring the cache kept fresh by
+// refreshHealthCache and falling back to a live call to the driver if nothing has been cached
+// yet, or if the cached entry is older than healthCacheTTL.
+func (c *Cluster) writeHealth(ctx context.Context, rw http.ResponseWriter, project func(managed.HealthStatus) interface{}) {
+	status, updated, ok := managed.CachedHealth(c.managedDB)
+	if !ok || time.Since(updated) > healthCacheTTL {
+		live, err := c.managedDB.Health(ctx)
+		switch {
+		case err == nil:
+			status = live
+		case ok:
+			logrus.Debugf("Failed to refresh data store health, serving stale cache from %s: %v", updated, err)
+		default:
+			http.Error(rw, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(rw).Encode(project(status))
+}
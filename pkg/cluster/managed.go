@@ -1,7 +1,8 @@
 package cluster
 
 // A managed database is one whose lifecycle we control - initializing the cluster, adding/removing members, taking snapshots, etc.
-// This is currently just used for the embedded etcd datastore. Kine and other external etcd clusters are NOT considered managed.
+// The embedded etcd datastore has always worked this way; other drivers implementing managed.Driver (see pkg/cluster/managed)
+// can be compiled in and selected via --datastore-endpoint=<driver>://... Kine and other external datastores are NOT considered managed.
 
 import (
 	"context"
@@ -18,8 +19,13 @@ import (
 	"github.com/rancher/k3s/pkg/version"
 	"github.com/rancher/kine/pkg/endpoint"
 	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/kubernetes"
 )
 
+// healthCacheRefreshInterval is how often refreshHealthCache polls the managed driver for a
+// fresh HealthStatus to back the /db/health family of HTTP endpoints.
+const healthCacheRefreshInterval = 5 * time.Second
+
 // testClusterDB returns a channel that will be closed when the datastore connection is available.
 // The datastore is tested for readiness every 5 seconds until the test succeeds.
 func (c *Cluster) testClusterDB(ctx context.Context) (<-chan struct{}, error) {
@@ -29,6 +35,8 @@ func (c *Cluster) testClusterDB(ctx context.Context) (<-chan struct{}, error) {
 		return result, nil
 	}
 
+	go c.refreshHealthCache(ctx)
+
 	go func() {
 		defer close(result)
 		for {
@@ -50,6 +58,29 @@ func (c *Cluster) testClusterDB(ctx context.Context) (<-chan struct{}, error) {
 	return result, nil
 }
 
+// refreshHealthCache polls the managed driver's Health method on healthCacheRefreshInterval and
+// stores each result in the shared cache consumed by the /db/health HTTP endpoints. Unlike the
+// one-shot readiness probe in testClusterDB, this keeps running for the lifetime of ctx, so that
+// leader changes, member churn, and alarms keep showing up after the cluster is already ready.
+func (c *Cluster) refreshHealthCache(ctx context.Context) {
+	t := time.NewTicker(healthCacheRefreshInterval)
+	defer t.Stop()
+
+	for {
+		if status, err := c.managedDB.Health(ctx); err != nil {
+			logrus.Debugf("Failed to collect data store health: %v", err)
+		} else {
+			managed.CacheHealth(c.managedDB, status)
+		}
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
 // cleanCerts removes existing certificatates previously
 // generated for use by the cluster.
 func (c *Cluster) cleanCerts() {
@@ -98,8 +129,10 @@ func (c *Cluster) cleanCerts() {
 }
 
 // start starts the database, unless a cluster reset has been requested, in which case
-// it does that instead.
-func (c *Cluster) start(ctx context.Context) error {
+// it does that instead. client and localNodeName are used to start the member reconciler
+// once the datastore is up; they are passed in by the caller rather than read from Cluster,
+// the same way setupEtcdProxy takes its etcdProxy argument.
+func (c *Cluster) start(ctx context.Context, client kubernetes.Interface, localNodeName string) error {
 	resetFile := etcd.ResetFile(c.config)
 	if c.managedDB == nil {
 		return nil
@@ -114,7 +147,17 @@ func (c *Cluster) start(ctx context.Context) error {
 			return fmt.Errorf("cluster-reset was successfully performed, please remove the cluster-reset flag and start %s normally, if you need to perform another cluster reset, you must first manually delete the %s file", version.Program, resetFile)
 		}
 
+		restorePath := c.config.ClusterResetRestorePath
 		rebootstrap := func() error {
+			if restorePath != "" {
+				name, err := c.restoreFromRemote(ctx, restorePath)
+				if err != nil {
+					return err
+				}
+				if err := c.managedDB.RestoreSnapshot(ctx, name); err != nil {
+					return err
+				}
+			}
 			return c.storageBootstrap(ctx)
 		}
 		if err := c.managedDB.Reset(ctx, rebootstrap, c.cleanCerts); err != nil {
@@ -124,7 +167,16 @@ func (c *Cluster) start(ctx context.Context) error {
 	// removing the reset file and ignore error if the file doesnt exist
 	os.Remove(resetFile)
 
-	return c.managedDB.Start(ctx, c.clientAccessInfo)
+	if err := c.managedDB.Start(ctx, c.clientAccessInfo); err != nil {
+		return err
+	}
+
+	if err := c.setupSnapshotScheduler(ctx); err != nil {
+		return err
+	}
+
+	c.SetupMemberReconciler(ctx, client, localNodeName)
+	return nil
 }
 
 // initClusterDB registers routes for database info with the http request handler
@@ -139,7 +191,12 @@ func (c *Cluster) initClusterDB(ctx context.Context, handler http.Handler) (http
 		}
 	}
 
-	return c.managedDB.Register(ctx, c.config, handler)
+	handler, err := c.managedDB.Register(ctx, c.config, handler)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.healthHandler(c.snapshotHandler(handler)), nil
 }
 
 // assignManagedDriver assigns a driver based on a number of different configuration variables.
@@ -181,23 +238,3 @@ func (c *Cluster) assignManagedDriver(ctx context.Context) error {
 
 	return nil
 }
-
-// setupEtcdProxy
-func (c *Cluster) setupEtcdProxy(ctx context.Context, etcdProxy etcd.Proxy) {
-	if c.managedDB == nil {
-		return
-	}
-	go func() {
-		t := time.NewTicker(30 * time.Second)
-		defer t.Stop()
-		for range t.C {
-			newAddresses, err := c.managedDB.GetMembersClientURLs(ctx)
-			if err != nil {
-				logrus.Warnf("failed to get etcd client URLs: %v", err)
-				continue
-			}
-			etcdProxy.Update(newAddresses)
-
-		}
-	}()
-}
@@ -0,0 +1,55 @@
+package managed
+
+import (
+	"sync"
+	"time"
+)
+
+// Member describes a single member of the datastore cluster for health reporting purposes. ID
+// is the datastore's own member identifier (for etcd, the raft-assigned member ID) and is NOT
+// guaranteed to match the backing Kubernetes node's name; Name carries that node name
+// separately, for callers such as the member reconciler that need to cross-reference the two.
+type Member struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	PeerURL   string `json:"peerURL"`
+	ClientURL string `json:"clientURL"`
+	Learner   bool   `json:"learner"`
+	RaftIndex uint64 `json:"raftIndex"`
+}
+
+// HealthStatus is the structured readiness snapshot returned by a Driver's Health method.
+type HealthStatus struct {
+	Healthy bool     `json:"healthy"`
+	Leader  string   `json:"leader"`
+	Alarms  []string `json:"alarms"`
+	Members []Member `json:"members"`
+}
+
+type healthCacheEntry struct {
+	status  HealthStatus
+	updated time.Time
+}
+
+var (
+	healthCacheMu sync.RWMutex
+	healthCache   = map[Driver]healthCacheEntry{}
+)
+
+// CacheHealth records the most recently observed HealthStatus for a driver. refreshHealthCache
+// (started by testClusterDB) calls this on every poll, so that the /db/health family of HTTP endpoints can serve cheap
+// reads without hitting the datastore on every request.
+func CacheHealth(d Driver, status HealthStatus) {
+	healthCacheMu.Lock()
+	defer healthCacheMu.Unlock()
+	healthCache[d] = healthCacheEntry{status: status, updated: time.Now()}
+}
+
+// CachedHealth returns the most recently cached HealthStatus for a driver, and whether one has
+// been recorded yet.
+func CachedHealth(d Driver) (HealthStatus, time.Time, bool) {
+	healthCacheMu.RLock()
+	defer healthCacheMu.RUnlock()
+	entry, ok := healthCache[d]
+	return entry.status, entry.updated, ok
+}
@@ -0,0 +1,134 @@
+// Package managed defines the interface implemented by managed datastore drivers, and
+// provides a registry that drivers use to advertise themselves. A managed datastore is one
+// whose lifecycle k3s controls directly: initializing the cluster, adding and removing
+// members, taking snapshots, and so on. Kine and other external datastores configured via
+// --datastore-endpoint are NOT managed, since k3s has no control over their lifecycle.
+//
+// Drivers are compiled in via blank import, typically gated behind a build tag, and register
+// themselves from an init() function by calling RegisterDriver.
+package managed
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/rancher/k3s/pkg/clientaccess"
+	"github.com/rancher/k3s/pkg/daemons/config"
+)
+
+// Driver is implemented by datastores whose lifecycle k3s manages directly.
+type Driver interface {
+	// EndpointName returns the datastore endpoint scheme handled by this driver, for example
+	// "etcd" for endpoints of the form etcd://... This is also used as the driver's name when
+	// selecting it via --datastore-endpoint=<name>://...
+	EndpointName() string
+
+	// IsInitialized returns true if this driver has already initialized a datastore on disk
+	// for the given configuration, so that it can be selected automatically on restart.
+	IsInitialized(ctx context.Context, config *config.Control) (bool, error)
+
+	// Register registers the driver's HTTP routes on the handler and returns the wrapped
+	// handler.
+	Register(ctx context.Context, config *config.Control, handler http.Handler) (http.Handler, error)
+
+	// Start starts the datastore, bootstrapping or joining a cluster as necessary.
+	Start(ctx context.Context, clientAccessInfo *clientaccess.Info) error
+
+	// Test checks that the datastore connection is healthy.
+	Test(ctx context.Context) error
+
+	// Reset wipes the existing datastore and reinitializes it with this node as the sole
+	// member. rebootstrap is called to seed the fresh datastore, and cleanCerts to remove
+	// certificates that are no longer valid once the cluster has been reset.
+	Reset(ctx context.Context, rebootstrap func() error, cleanCerts func()) error
+
+	// Snapshot takes an on-demand snapshot of the datastore.
+	Snapshot(ctx context.Context, config *config.Control) error
+
+	// ListSnapshots returns the snapshots currently stored on local disk, newest first.
+	ListSnapshots(ctx context.Context) ([]SnapshotFile, error)
+
+	// DeleteSnapshot removes the named local snapshot.
+	DeleteSnapshot(ctx context.Context, name string) error
+
+	// RestoreSnapshot replaces the datastore's contents with the named local snapshot. It is
+	// called before rebootstrap during a --cluster-reset-restore-path reset.
+	RestoreSnapshot(ctx context.Context, name string) error
+
+	// GetMembersClientURLs returns the client URLs of all members of the datastore cluster.
+	GetMembersClientURLs(ctx context.Context) ([]string, error)
+
+	// WatchMembers returns a channel on which the full list of member client URLs is sent
+	// every time cluster membership changes, so that callers such as setupEtcdProxy can react
+	// to churn immediately rather than polling GetMembersClientURLs. The channel is closed once
+	// ctx is done; a driver that loses its watch may also close it earlier, in which case the
+	// watch must be reestablished by calling WatchMembers again.
+	WatchMembers(ctx context.Context) (<-chan []string, error)
+
+	// Health returns a structured readiness snapshot of the datastore, for the /db/health,
+	// /db/members, and /db/leader HTTP endpoints.
+	Health(ctx context.Context) (HealthStatus, error)
+
+	// AddAsLearner adds the member reachable at clientURL as a non-voting learner, returning
+	// its assigned member ID. Newly joining servers are added this way so that they cannot
+	// disrupt quorum until their raft log has caught up.
+	AddAsLearner(ctx context.Context, clientURL string) (string, error)
+
+	// PromoteMember promotes the learner with the given member ID to a full voting member.
+	// Callers are expected to have already confirmed the learner has caught up via Health.
+	PromoteMember(ctx context.Context, memberID string) error
+
+	// RemoveMember removes the member with the given member ID from the datastore cluster,
+	// for example once the Kubernetes node it backed has been deleted.
+	RemoveMember(ctx context.Context, memberID string) error
+}
+
+var (
+	driversMu     sync.Mutex
+	drivers       = map[string]Driver{}
+	defaultDriver string
+)
+
+// RegisterDriver adds a driver to the registry under its EndpointName. It is intended to be
+// called from a driver's init() function, and panics if another driver is already registered
+// under the same name.
+func RegisterDriver(d Driver) {
+	driversMu.Lock()
+	defer driversMu.Unlock()
+
+	name := d.EndpointName()
+	if _, ok := drivers[name]; ok {
+		panic("managed: driver already registered for " + name)
+	}
+	drivers[name] = d
+}
+
+// SetDefault marks the named driver as the one to use when cluster init or join has been
+// requested but no explicit datastore endpoint has been configured. Only one driver may be
+// registered as the default; the embedded etcd driver claims this today.
+func SetDefault(name string) {
+	driversMu.Lock()
+	defer driversMu.Unlock()
+	defaultDriver = name
+}
+
+// Default returns the endpoint name of the default managed driver, or an empty string if no
+// driver has claimed the default.
+func Default() string {
+	driversMu.Lock()
+	defer driversMu.Unlock()
+	return defaultDriver
+}
+
+// Registered returns all currently registered managed drivers.
+func Registered() []Driver {
+	driversMu.Lock()
+	defer driversMu.Unlock()
+
+	reg := make([]Driver, 0, len(drivers))
+	for _, d := range drivers {
+		reg = append(reg, d)
+	}
+	return reg
+}
@@ -0,0 +1,94 @@
+package managed
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rancher/k3s/pkg/daemons/config"
+	"github.com/robfig/cron/v3"
+	"github.com/sirupsen/logrus"
+)
+
+// SnapshotScheduler periodically triggers snapshots on a Driver according to a cron schedule,
+// uploads them to Backend if one is configured, and prunes old snapshots according to
+// Retention once a run completes.
+type SnapshotScheduler struct {
+	Driver    Driver
+	Config    *config.Control
+	Cron      string
+	Retention RetentionPolicy
+	Backend   Backend
+}
+
+// Run parses s.Cron and starts a background goroutine that takes snapshots on schedule until
+// ctx is cancelled. It returns immediately; a malformed cron expression is returned as an
+// error rather than only surfacing when the schedule next fires. An empty Cron disables the
+// scheduler entirely.
+func (s *SnapshotScheduler) Run(ctx context.Context) error {
+	if s.Cron == "" {
+		return nil
+	}
+
+	schedule, err := cron.ParseStandard(s.Cron)
+	if err != nil {
+		return fmt.Errorf("invalid etcd-snapshot-schedule-cron %q: %w", s.Cron, err)
+	}
+
+	go func() {
+		next := schedule.Next(time.Now())
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Until(next)):
+				s.runOnce(ctx)
+				next = schedule.Next(time.Now())
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (s *SnapshotScheduler) runOnce(ctx context.Context) {
+	if err := s.Driver.Snapshot(ctx, s.Config); err != nil {
+		logrus.Errorf("Scheduled snapshot failed: %v", err)
+		return
+	}
+
+	if s.Backend != nil {
+		if err := uploadLatest(ctx, s.Driver, s.Backend); err != nil {
+			logrus.Errorf("Failed to upload snapshot to %s: %v", s.Backend.Name(), err)
+		}
+	}
+
+	if err := s.prune(ctx); err != nil {
+		logrus.Errorf("Snapshot retention cleanup failed: %v", err)
+	}
+}
+
+func uploadLatest(ctx context.Context, d Driver, backend Backend) error {
+	files, err := d.ListSnapshots(ctx)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return nil
+	}
+	latest := files[0]
+	return backend.Upload(ctx, latest.Location, latest)
+}
+
+func (s *SnapshotScheduler) prune(ctx context.Context) error {
+	files, err := s.Driver.ListSnapshots(ctx)
+	if err != nil {
+		return err
+	}
+	for _, f := range s.Retention.Apply(files) {
+		if err := s.Driver.DeleteSnapshot(ctx, f.Name); err != nil {
+			logrus.Warnf("Failed to prune snapshot %s: %v", f.Name, err)
+		}
+	}
+	return nil
+}
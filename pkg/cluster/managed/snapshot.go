@@ -0,0 +1,83 @@
+package managed
+
+import (
+	"context"
+	"time"
+)
+
+// SnapshotFile describes a single datastore snapshot, whether it lives on local disk or has
+// been uploaded to a remote Backend.
+type SnapshotFile struct {
+	Name      string    `json:"name"`
+	Location  string    `json:"location"`
+	NodeName  string    `json:"nodeName"`
+	CreatedAt time.Time `json:"createdAt"`
+	Size      int64     `json:"size"`
+}
+
+// Backend uploads and downloads snapshots to and from a remote location, so that snapshots
+// survive the loss of the node that took them. Drivers never talk to a Backend themselves:
+// SnapshotScheduler uploads the newest of a driver's ListSnapshots to it, and
+// pkg/cluster/snapshot.go downloads from it before calling the driver's RestoreSnapshot.
+type Backend interface {
+	// Name identifies the backend, eg "s3" or "sftp", for logging and status reporting.
+	Name() string
+
+	// Upload copies the local snapshot at path to the backend.
+	Upload(ctx context.Context, path string, file SnapshotFile) error
+
+	// Download fetches file from the backend into destDir, returning the local path.
+	Download(ctx context.Context, file SnapshotFile, destDir string) (string, error)
+
+	// List returns the snapshots currently stored on the backend.
+	List(ctx context.Context) ([]SnapshotFile, error)
+}
+
+// RetentionPolicy determines which of a driver's snapshots should be pruned. The keep-last
+// and keep-daily rules are additive: a snapshot is retained if either rule would keep it. If
+// both are zero, the policy keeps everything, matching the "0 means unlimited" convention of
+// --etcd-snapshot-retention rather than pruning every snapshot the instant it's taken.
+type RetentionPolicy struct {
+	// KeepLast retains the N most recent snapshots regardless of age. Zero disables the rule.
+	KeepLast int
+	// KeepDaily retains the most recent snapshot from each of the last N distinct days. Zero
+	// disables the rule.
+	KeepDaily int
+}
+
+// Apply returns the subset of files that should be deleted to satisfy the policy. files is
+// expected to be sorted newest-first by CreatedAt.
+func (p RetentionPolicy) Apply(files []SnapshotFile) []SnapshotFile {
+	if p.KeepLast <= 0 && p.KeepDaily <= 0 {
+		return nil
+	}
+
+	keep := make(map[string]bool, len(files))
+
+	for i, f := range files {
+		if p.KeepLast > 0 && i < p.KeepLast {
+			keep[f.Name] = true
+		}
+	}
+
+	seenDays := map[string]bool{}
+	for _, f := range files {
+		if p.KeepDaily <= 0 || len(seenDays) >= p.KeepDaily {
+			continue
+		}
+		day := f.CreatedAt.Format("2006-01-02")
+		if seenDays[day] {
+			continue
+		}
+		seenDays[day] = true
+		keep[f.Name] = true
+	}
+
+	prune := make([]SnapshotFile, 0, len(files))
+	for _, f := range files {
+		if !keep[f.Name] {
+			prune = append(prune, f)
+		}
+	}
+	return prune
+}
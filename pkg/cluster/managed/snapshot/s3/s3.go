@@ -0,0 +1,92 @@
+// Package s3 implements a managed.Backend that stores snapshots in an S3-compatible object
+// store, configured via the --etcd-s3-* CLI flags.
+package s3
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/rancher/k3s/pkg/cluster/managed"
+)
+
+// Config holds the settings needed to reach an S3-compatible bucket.
+type Config struct {
+	Endpoint  string
+	Bucket    string
+	Region    string
+	Folder    string
+	AccessKey string
+	SecretKey string
+	Insecure  bool
+}
+
+// Backend is a managed.Backend that stores snapshots in an S3-compatible bucket.
+type Backend struct {
+	config Config
+	client *minio.Client
+}
+
+// New creates a Backend for the given S3 configuration.
+func New(cfg Config) (*Backend, error) {
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure: !cfg.Insecure,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("s3: failed to create client: %w", err)
+	}
+	return &Backend{config: cfg, client: client}, nil
+}
+
+// Name implements managed.Backend.
+func (b *Backend) Name() string {
+	return "s3"
+}
+
+func (b *Backend) key(name string) string {
+	return path.Join(b.config.Folder, name)
+}
+
+// Upload implements managed.Backend.
+func (b *Backend) Upload(ctx context.Context, localPath string, file managed.SnapshotFile) error {
+	_, err := b.client.FPutObject(ctx, b.config.Bucket, b.key(file.Name), localPath, minio.PutObjectOptions{})
+	if err != nil {
+		return fmt.Errorf("s3: failed to upload %s: %w", file.Name, err)
+	}
+	return nil
+}
+
+// Download implements managed.Backend.
+func (b *Backend) Download(ctx context.Context, file managed.SnapshotFile, destDir string) (string, error) {
+	dest := filepath.Join(destDir, file.Name)
+	if err := os.MkdirAll(destDir, 0700); err != nil {
+		return "", err
+	}
+	if err := b.client.FGetObject(ctx, b.config.Bucket, b.key(file.Name), dest, minio.GetObjectOptions{}); err != nil {
+		return "", fmt.Errorf("s3: failed to download %s: %w", file.Name, err)
+	}
+	return dest, nil
+}
+
+// List implements managed.Backend.
+func (b *Backend) List(ctx context.Context) ([]managed.SnapshotFile, error) {
+	var files []managed.SnapshotFile
+	for obj := range b.client.ListObjects(ctx, b.config.Bucket, minio.ListObjectsOptions{Prefix: b.config.Folder, Recursive: true}) {
+		if obj.Err != nil {
+			return nil, fmt.Errorf("s3: failed to list objects: %w", obj.Err)
+		}
+		files = append(files, managed.SnapshotFile{
+			Name:      filepath.Base(obj.Key),
+			Location:  fmt.Sprintf("s3://%s/%s", b.config.Bucket, obj.Key),
+			CreatedAt: obj.LastModified,
+			Size:      obj.Size,
+		})
+	}
+	return files, nil
+}
@@ -0,0 +1,240 @@
+//go:build embedded_sqlite
+
+// Package sqlite implements a managed.Driver backed by an embedded SQLite database. Unlike
+// etcd, SQLite has no concept of cluster membership: the datastore only ever has one member,
+// the local node, so joining, adding and removing members are all no-ops or errors as noted
+// on the individual methods.
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/rancher/k3s/pkg/clientaccess"
+	"github.com/rancher/k3s/pkg/cluster/managed"
+	"github.com/rancher/k3s/pkg/daemons/config"
+	"github.com/sirupsen/logrus"
+)
+
+func init() {
+	managed.RegisterDriver(&Driver{})
+}
+
+// Driver is a managed.Driver backed by an embedded SQLite database file.
+type Driver struct {
+	dbPath string
+}
+
+func (d *Driver) snapshotDir() string {
+	return filepath.Join(filepath.Dir(d.dbPath), "snapshots")
+}
+
+// EndpointName implements managed.Driver.
+func (d *Driver) EndpointName() string {
+	return "sqlite"
+}
+
+func (d *Driver) dataDir(config *config.Control) string {
+	return filepath.Join(config.DataDir, "db")
+}
+
+func (d *Driver) dbFile(config *config.Control) string {
+	return filepath.Join(d.dataDir(config), "state.db")
+}
+
+// IsInitialized implements managed.Driver.
+func (d *Driver) IsInitialized(ctx context.Context, config *config.Control) (bool, error) {
+	if _, err := os.Stat(d.dbFile(config)); err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Register implements managed.Driver. SQLite has no server component to register routes
+// against, so the handler is returned unmodified.
+func (d *Driver) Register(ctx context.Context, config *config.Control, handler http.Handler) (http.Handler, error) {
+	d.dbPath = d.dbFile(config)
+	return handler, nil
+}
+
+// Start implements managed.Driver. SQLite is always single-member, so there is no bootstrap
+// or join handshake to perform beyond ensuring the data directory exists.
+func (d *Driver) Start(ctx context.Context, clientAccessInfo *clientaccess.Info) error {
+	if d.dbPath == "" {
+		return fmt.Errorf("sqlite: driver has not been registered")
+	}
+	if err := os.MkdirAll(filepath.Dir(d.dbPath), 0700); err != nil {
+		return err
+	}
+	logrus.Infof("Managed sqlite datastore starting using file %s", d.dbPath)
+	return nil
+}
+
+// Test implements managed.Driver.
+func (d *Driver) Test(ctx context.Context) error {
+	f, err := os.OpenFile(d.dbPath, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// Reset implements managed.Driver by removing the existing database file and invoking
+// rebootstrap to seed a fresh one.
+func (d *Driver) Reset(ctx context.Context, rebootstrap func() error, cleanCerts func()) error {
+	if err := os.Remove(d.dbPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	cleanCerts()
+	return rebootstrap()
+}
+
+// Snapshot implements managed.Driver by copying the database file to a timestamped file in
+// the snapshot directory.
+func (d *Driver) Snapshot(ctx context.Context, config *config.Control) error {
+	if err := os.MkdirAll(d.snapshotDir(), 0700); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("state-%d.db", time.Now().Unix())
+	if err := copyFile(d.dbPath, filepath.Join(d.snapshotDir(), name)); err != nil {
+		return err
+	}
+
+	logrus.Infof("Saved sqlite snapshot to %s", filepath.Join(d.snapshotDir(), name))
+	return nil
+}
+
+// ListSnapshots implements managed.Driver.
+func (d *Driver) ListSnapshots(ctx context.Context) ([]managed.SnapshotFile, error) {
+	entries, err := os.ReadDir(d.snapshotDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	files := make([]managed.SnapshotFile, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, managed.SnapshotFile{
+			Name:      e.Name(),
+			Location:  filepath.Join(d.snapshotDir(), e.Name()),
+			CreatedAt: info.ModTime(),
+			Size:      info.Size(),
+		})
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].CreatedAt.After(files[j].CreatedAt) })
+	return files, nil
+}
+
+// DeleteSnapshot implements managed.Driver.
+func (d *Driver) DeleteSnapshot(ctx context.Context, name string) error {
+	return os.Remove(filepath.Join(d.snapshotDir(), name))
+}
+
+// RestoreSnapshot implements managed.Driver by overwriting the live database file with the
+// named snapshot. Callers are expected to have stopped the datastore first.
+func (d *Driver) RestoreSnapshot(ctx context.Context, name string) error {
+	return copyFile(filepath.Join(d.snapshotDir(), name), d.dbPath)
+}
+
+// AddAsLearner implements managed.Driver. SQLite has no concept of cluster membership, so
+// joining a second member is not supported.
+func (d *Driver) AddAsLearner(ctx context.Context, clientURL string) (string, error) {
+	return "", fmt.Errorf("sqlite: datastore does not support additional members")
+}
+
+// PromoteMember implements managed.Driver. SQLite never has learners to promote.
+func (d *Driver) PromoteMember(ctx context.Context, memberID string) error {
+	return fmt.Errorf("sqlite: datastore does not support additional members")
+}
+
+// RemoveMember implements managed.Driver. SQLite never has members to remove.
+func (d *Driver) RemoveMember(ctx context.Context, memberID string) error {
+	return fmt.Errorf("sqlite: datastore does not support additional members")
+}
+
+// GetMembersClientURLs implements managed.Driver. SQLite is single-member, so this always
+// returns the local kine endpoint.
+func (d *Driver) GetMembersClientURLs(ctx context.Context) ([]string, error) {
+	return []string{"sqlite://" + d.dbPath}, nil
+}
+
+// WatchMembers implements managed.Driver. SQLite is single-member and membership never
+// changes, so the channel is sent the local member once and then closed when ctx is done.
+func (d *Driver) WatchMembers(ctx context.Context) (<-chan []string, error) {
+	ch := make(chan []string, 1)
+	urls, err := d.GetMembersClientURLs(ctx)
+	if err != nil {
+		close(ch)
+		return nil, err
+	}
+	ch <- urls
+
+	go func() {
+		<-ctx.Done()
+		close(ch)
+	}()
+
+	return ch, nil
+}
+
+// Health implements managed.Driver. SQLite is single-member and has no raft log, alarms, or
+// leader election, so the node is healthy whenever the database file can be opened.
+func (d *Driver) Health(ctx context.Context) (managed.HealthStatus, error) {
+	if err := d.Test(ctx); err != nil {
+		return managed.HealthStatus{Healthy: false, Alarms: []string{err.Error()}}, nil
+	}
+
+	nodeName, err := os.Hostname()
+	if err != nil {
+		nodeName = "local"
+	}
+
+	return managed.HealthStatus{
+		Healthy: true,
+		Leader:  "local",
+		Members: []managed.Member{
+			{
+				ID:        "local",
+				Name:      nodeName,
+				ClientURL: "sqlite://" + d.dbPath,
+			},
+		},
+	}, nil
+}
+
+func copyFile(srcPath, dstPath string) error {
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(dstPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	_, err = io.Copy(dst, src)
+	return err
+}
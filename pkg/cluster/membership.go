@@ -0,0 +1,141 @@
+package cluster
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	memberReconcileInterval = 30 * time.Second
+	// learnerCatchUpThreshold is how far behind the most caught-up member's raft index a
+	// learner may be and still be considered ready for promotion to a voting member.
+	learnerCatchUpThreshold = 100
+)
+
+// SetupMemberReconciler starts a controller loop that keeps datastore membership in sync with
+// Kubernetes node membership: learners added via managed.Driver.AddAsLearner are promoted to
+// voting members once their raft log has caught up, and members whose backing node no longer
+// exists in Kubernetes are removed to keep quorum healthy; the node's Ready condition is not
+// checked. client and
+// localNodeName are passed in by the caller rather than read from Cluster, mirroring
+// setupEtcdProxy's explicit etcdProxy parameter; localNodeName is used to make sure the
+// reconciler never removes the member backing the node it is running on.
+func (c *Cluster) SetupMemberReconciler(ctx context.Context, client kubernetes.Interface, localNodeName string) {
+	if c.managedDB == nil {
+		return
+	}
+	go c.runMemberReconciler(ctx, client, localNodeName)
+}
+
+func (c *Cluster) runMemberReconciler(ctx context.Context, client kubernetes.Interface, localNodeName string) {
+	t := time.NewTicker(memberReconcileInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if err := c.promoteCaughtUpLearners(ctx); err != nil {
+				logrus.Warnf("failed to promote caught-up datastore learners: %v", err)
+			}
+			if err := c.removeDepartedMembers(ctx, client, localNodeName); err != nil {
+				logrus.Warnf("failed to remove departed datastore members: %v", err)
+			}
+		}
+	}
+}
+
+// promoteCaughtUpLearners promotes any learner whose raft index is within
+// learnerCatchUpThreshold entries of the most caught-up member.
+func (c *Cluster) promoteCaughtUpLearners(ctx context.Context) error {
+	status, err := c.managedDB.Health(ctx)
+	if err != nil {
+		return err
+	}
+
+	var maxIndex uint64
+	for _, m := range status.Members {
+		if m.RaftIndex > maxIndex {
+			maxIndex = m.RaftIndex
+		}
+	}
+
+	for _, m := range status.Members {
+		if !m.Learner || maxIndex-m.RaftIndex > learnerCatchUpThreshold {
+			continue
+		}
+		if err := c.managedDB.PromoteMember(ctx, m.ID); err != nil {
+			logrus.Warnf("failed to promote learner %s: %v", m.ID, err)
+			continue
+		}
+		logrus.Infof("Promoted datastore learner %s to voting member", m.ID)
+	}
+
+	return nil
+}
+
+// removeDepartedMembers removes datastore members whose backing Kubernetes node no longer
+// exists, so that a node which never comes back doesn't hold quorum hostage. A member is
+// removed as soon as its node is not found; whether the node was NotReady before it was deleted
+// is not checked. Members are matched to nodes by Member.Name, which Health fills in from the
+// datastore's own member name rather than its member ID - for etcd those are two different
+// identifiers, and looking up a node by the raft member ID would never find one.
+//
+// The local node's own member is never considered for removal, and a voting member is only
+// removed if doing so leaves at least a quorum of the voting members observed at the start of
+// this pass, so that a burst of departures in one tick can't be pruned down past quorum.
+func (c *Cluster) removeDepartedMembers(ctx context.Context, client kubernetes.Interface, localNodeName string) error {
+	status, err := c.managedDB.Health(ctx)
+	if err != nil {
+		return err
+	}
+
+	voters := 0
+	for _, m := range status.Members {
+		if !m.Learner {
+			voters++
+		}
+	}
+	// quorum is fixed from the voter count observed at the start of this pass, so a burst of
+	// departures in a single tick can prune down to quorum and no further, rather than each
+	// removal making the next one easier by shrinking the threshold along with voters.
+	quorum := voters/2 + 1
+
+	for _, m := range status.Members {
+		if m.Name == "" || m.Name == localNodeName {
+			continue
+		}
+
+		_, err := client.CoreV1().Nodes().Get(ctx, m.Name, metav1.GetOptions{})
+		if err == nil {
+			continue
+		}
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+
+		if !m.Learner {
+			if voters-1 < quorum {
+				logrus.Warnf("refusing to remove voting member %s (%s): would drop below quorum (%d of %d voters)", m.Name, m.ID, voters-1, voters)
+				continue
+			}
+		}
+
+		logrus.Infof("Removing datastore member %s (%s) for deleted node", m.Name, m.ID)
+		if err := c.managedDB.RemoveMember(ctx, m.ID); err != nil {
+			logrus.Warnf("failed to remove datastore member %s: %v", m.ID, err)
+			continue
+		}
+		if !m.Learner {
+			voters--
+		}
+	}
+
+	return nil
+}
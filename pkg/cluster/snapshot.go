@@ -0,0 +1,153 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/rancher/k3s/pkg/cluster/managed"
+	"github.com/rancher/k3s/pkg/cluster/managed/snapshot/s3"
+	"github.com/rancher/k3s/pkg/cluster/managed/snapshot/sftp"
+	"github.com/sirupsen/logrus"
+)
+
+// setupSnapshotScheduler starts the scheduled snapshot subsystem for the managed driver, if
+// --etcd-snapshot-schedule-cron has been configured.
+func (c *Cluster) setupSnapshotScheduler(ctx context.Context) error {
+	if c.managedDB == nil || c.config.EtcdSnapshotScheduleCron == "" {
+		return nil
+	}
+
+	scheduler := &managed.SnapshotScheduler{
+		Driver: c.managedDB,
+		Config: c.config,
+		Cron:   c.config.EtcdSnapshotScheduleCron,
+		Retention: managed.RetentionPolicy{
+			KeepLast:  c.config.EtcdSnapshotRetention,
+			KeepDaily: c.config.EtcdSnapshotRetentionDaily,
+		},
+		Backend: c.snapshotBackend(),
+	}
+	return scheduler.Run(ctx)
+}
+
+// snapshotBackend returns the remote snapshot Backend configured via the --etcd-s3-* or
+// --etcd-sftp-* flags, or nil if no remote backend has been configured.
+func (c *Cluster) snapshotBackend() managed.Backend {
+	switch {
+	case c.config.EtcdS3.Bucket != "":
+		b, err := s3.New(s3.Config{
+			Endpoint:  c.config.EtcdS3.Endpoint,
+			Bucket:    c.config.EtcdS3.Bucket,
+			Region:    c.config.EtcdS3.Region,
+			Folder:    c.config.EtcdS3.Folder,
+			AccessKey: c.config.EtcdS3.AccessKey,
+			SecretKey: c.config.EtcdS3.SecretKey,
+			Insecure:  c.config.EtcdS3.Insecure,
+		})
+		if err != nil {
+			logrus.Errorf("Failed to configure S3 snapshot backend: %v", err)
+			return nil
+		}
+		return b
+	case c.config.EtcdSFTP.Address != "":
+		return sftp.New(sftp.Config{
+			Address:  c.config.EtcdSFTP.Address,
+			Username: c.config.EtcdSFTP.Username,
+			Password: c.config.EtcdSFTP.Password,
+			Folder:   c.config.EtcdSFTP.Folder,
+		})
+	default:
+		return nil
+	}
+}
+
+// snapshotHandler serves the /db/snapshot HTTP API for listing, on-demand creation, and
+// restoration of managed datastore snapshots, falling through to next for any other path.
+func (c *Cluster) snapshotHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if c.managedDB == nil || !strings.HasPrefix(req.URL.Path, "/db/snapshot") {
+			next.ServeHTTP(rw, req)
+			return
+		}
+
+		ctx := req.Context()
+		switch {
+		case req.URL.Path == "/db/snapshot" && req.Method == http.MethodGet:
+			c.listSnapshots(ctx, rw)
+		case req.URL.Path == "/db/snapshot" && req.Method == http.MethodPost:
+			c.triggerSnapshot(ctx, rw)
+		case req.URL.Path == "/db/snapshot/restore" && req.Method == http.MethodPost:
+			c.restoreSnapshot(ctx, rw, req)
+		default:
+			http.NotFound(rw, req)
+		}
+	})
+}
+
+func (c *Cluster) listSnapshots(ctx context.Context, rw http.ResponseWriter) {
+	files, err := c.managedDB.ListSnapshots(ctx)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(rw).Encode(files)
+}
+
+func (c *Cluster) triggerSnapshot(ctx context.Context, rw http.ResponseWriter) {
+	if err := c.managedDB.Snapshot(ctx, c.config); err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rw.WriteHeader(http.StatusAccepted)
+}
+
+func (c *Cluster) restoreSnapshot(ctx context.Context, rw http.ResponseWriter, req *http.Request) {
+	var body struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil || body.Name == "" {
+		http.Error(rw, "name is required", http.StatusBadRequest)
+		return
+	}
+	if err := c.managedDB.RestoreSnapshot(ctx, body.Name); err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rw.WriteHeader(http.StatusAccepted)
+}
+
+// restoreFromRemote resolves --cluster-reset-restore-path to a local snapshot name, downloading
+// it from the configured remote backend first if needed. If no remote backend is configured, or
+// if restorePath doesn't match any snapshot on the configured backend, it is returned unchanged
+// on the assumption that it already names a local snapshot - an operator with a remote backend
+// configured can still restore from a snapshot that only ever existed on local disk.
+func (c *Cluster) restoreFromRemote(ctx context.Context, restorePath string) (string, error) {
+	backend := c.snapshotBackend()
+	if backend == nil {
+		return restorePath, nil
+	}
+
+	files, err := backend.List(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list snapshots on %s backend: %w", backend.Name(), err)
+	}
+
+	for _, f := range files {
+		if f.Name != restorePath {
+			continue
+		}
+		localPath, err := backend.Download(ctx, f, filepath.Join(c.config.DataDir, "db", "snapshots"))
+		if err != nil {
+			return "", fmt.Errorf("failed to download snapshot %s from %s backend: %w", f.Name, backend.Name(), err)
+		}
+		return filepath.Base(localPath), nil
+	}
+
+	logrus.Infof("Snapshot %s not found on %s backend, assuming it is a local snapshot", restorePath, backend.Name())
+	return restorePath, nil
+}
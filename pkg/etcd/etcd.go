@@ -0,0 +1,183 @@
+// Package etcd implements a managed.Driver backed by an embedded etcd cluster. It is the
+// original managed datastore and remains the default driver (see init() below); other drivers
+// such as pkg/cluster/managed/sqlite implement the same interface for storage backends that
+// don't need etcd's clustering.
+package etcd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/rancher/k3s/pkg/clientaccess"
+	"github.com/rancher/k3s/pkg/cluster/managed"
+	"github.com/rancher/k3s/pkg/daemons/config"
+	"github.com/sirupsen/logrus"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+const (
+	endpointName = "etcd"
+	dialTimeout  = 5 * time.Second
+)
+
+func init() {
+	managed.RegisterDriver(&ETCD{})
+	managed.SetDefault(endpointName)
+}
+
+// ETCD is a managed.Driver backed by an embedded etcd cluster.
+type ETCD struct {
+	config    *config.Control
+	name      string
+	clientURL string
+	dataDir   string
+}
+
+// EndpointName implements managed.Driver.
+func (e *ETCD) EndpointName() string {
+	return endpointName
+}
+
+// IsInitialized implements managed.Driver by checking whether etcd has already written its
+// raft write-ahead log to disk for this data directory.
+func (e *ETCD) IsInitialized(ctx context.Context, config *config.Control) (bool, error) {
+	if _, err := os.Stat(filepath.Join(config.DataDir, "db", "etcd", "member", "wal")); err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Register implements managed.Driver. etcd has no extra HTTP routes of its own to register;
+// the /db/health, /db/members, /db/leader, and /db/snapshot families in front of this driver
+// are wired in by pkg/cluster regardless of which managed.Driver is active.
+func (e *ETCD) Register(ctx context.Context, config *config.Control, handler http.Handler) (http.Handler, error) {
+	e.config = config
+	e.name = config.ServerNodeName
+	e.clientURL = config.Datastore.Endpoint
+	if e.clientURL == "" || e.clientURL == endpointName {
+		e.clientURL = "https://127.0.0.1:2379"
+	}
+	e.dataDir = filepath.Join(config.DataDir, "db", "etcd")
+	return handler, nil
+}
+
+// Start implements managed.Driver. Starting the embedded etcd server process itself is handled
+// by the runtime's bootstrap code; Start's job here is to make sure the data directory exists
+// and, for a node joining an existing cluster, to perform the membership handshake: it adds
+// itself as a learner first, so that it cannot disrupt quorum until promoteCaughtUpLearners
+// (pkg/cluster/membership.go) promotes it once its raft log has caught up.
+func (e *ETCD) Start(ctx context.Context, clientAccessInfo *clientaccess.Info) error {
+	if err := os.MkdirAll(e.dataDir, 0700); err != nil {
+		return err
+	}
+
+	if e.config.JoinURL != "" {
+		if err := e.join(ctx, clientAccessInfo); err != nil {
+			return fmt.Errorf("etcd: failed to join cluster as learner: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// join adds this node to an existing cluster as a learner, dialing an existing member through
+// clientAccessInfo rather than the local client URL - this node isn't part of the cluster yet,
+// so there's no local member to administer it through.
+func (e *ETCD) join(ctx context.Context, clientAccessInfo *clientaccess.Info) error {
+	client, err := e.dialEndpoints(ctx, []string{clientAccessInfo.BaseURL})
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	resp, err := client.MemberAddAsLearner(ctx, []string{e.clientURL})
+	if err != nil {
+		return fmt.Errorf("failed to add %s as learner: %w", e.clientURL, err)
+	}
+	e.bumpMembersVersion(ctx, client)
+
+	logrus.Infof("Added %s to etcd cluster as learner %s; waiting for it to catch up before promotion", e.name, memberIDToString(resp.Member.ID))
+	return nil
+}
+
+// Test implements managed.Driver.
+func (e *ETCD) Test(ctx context.Context) error {
+	client, err := e.getClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	_, err = client.MemberList(ctx)
+	return err
+}
+
+// Reset implements managed.Driver by wiping the local etcd data directory and rebootstrapping
+// as the sole member of a new cluster.
+func (e *ETCD) Reset(ctx context.Context, rebootstrap func() error, cleanCerts func()) error {
+	if err := os.RemoveAll(e.dataDir); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	cleanCerts()
+	return rebootstrap()
+}
+
+// GetMembersClientURLs implements managed.Driver.
+func (e *ETCD) GetMembersClientURLs(ctx context.Context) ([]string, error) {
+	client, err := e.getClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	return e.getMembersClientURLs(ctx, client)
+}
+
+func (e *ETCD) getMembersClientURLs(ctx context.Context, client *clientv3.Client) ([]string, error) {
+	resp, err := client.MemberList(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("etcd: failed to list members: %w", err)
+	}
+
+	urls := make([]string, 0, len(resp.Members))
+	for _, m := range resp.Members {
+		urls = append(urls, m.ClientURLs...)
+	}
+	return urls, nil
+}
+
+// getClient dials the locally running etcd member for cluster administration calls.
+func (e *ETCD) getClient(ctx context.Context) (*clientv3.Client, error) {
+	return e.dialEndpoints(ctx, []string{e.clientURL})
+}
+
+// dialEndpoints dials an etcd client against the given endpoints. Production TLS is sourced
+// from the cluster's etcd certificates; omitted here to keep this file focused on the
+// managed.Driver lifecycle and membership logic.
+func (e *ETCD) dialEndpoints(ctx context.Context, endpoints []string) (*clientv3.Client, error) {
+	return clientv3.New(clientv3.Config{
+		Endpoints:   endpoints,
+		DialTimeout: dialTimeout,
+		Context:     ctx,
+	})
+}
+
+func memberIDToString(id uint64) string {
+	return strconv.FormatUint(id, 16)
+}
+
+func memberIDFromString(id string) (uint64, error) {
+	v, err := strconv.ParseUint(id, 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("etcd: invalid member ID %q: %w", id, err)
+	}
+	return v, nil
+}
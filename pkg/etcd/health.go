@@ -0,0 +1,65 @@
+package etcd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rancher/k3s/pkg/cluster/managed"
+	"github.com/sirupsen/logrus"
+)
+
+// Health implements managed.Driver, reporting each member's raft index, learner state, and
+// client/peer URLs from MemberList, and the cluster leader and any per-member alarms from
+// Status, which promoteCaughtUpLearners and removeDepartedMembers (pkg/cluster/membership.go)
+// use to drive learner promotion and quorum-safe member removal.
+func (e *ETCD) Health(ctx context.Context) (managed.HealthStatus, error) {
+	client, err := e.getClient(ctx)
+	if err != nil {
+		return managed.HealthStatus{}, err
+	}
+	defer client.Close()
+
+	listResp, err := client.MemberList(ctx)
+	if err != nil {
+		return managed.HealthStatus{}, fmt.Errorf("etcd: failed to list members: %w", err)
+	}
+
+	status := managed.HealthStatus{Healthy: true}
+	var leaderID uint64
+
+	for _, m := range listResp.Members {
+		member := managed.Member{
+			ID:      memberIDToString(m.ID),
+			Name:    m.Name,
+			Learner: m.IsLearner,
+		}
+		if len(m.PeerURLs) > 0 {
+			member.PeerURL = m.PeerURLs[0]
+		}
+		if len(m.ClientURLs) > 0 {
+			member.ClientURL = m.ClientURLs[0]
+
+			if s, err := client.Status(ctx, m.ClientURLs[0]); err != nil {
+				logrus.Debugf("etcd: failed to get status for member %s: %v", m.Name, err)
+				status.Healthy = false
+				status.Alarms = append(status.Alarms, fmt.Sprintf("%s: %v", m.Name, err))
+			} else {
+				member.RaftIndex = s.RaftIndex
+				if s.Leader == m.ID {
+					leaderID = m.ID
+				}
+				for _, alarm := range s.Errors {
+					status.Alarms = append(status.Alarms, fmt.Sprintf("%s: %s", m.Name, alarm))
+				}
+			}
+		}
+
+		status.Members = append(status.Members, member)
+	}
+
+	if leaderID != 0 {
+		status.Leader = memberIDToString(leaderID)
+	}
+
+	return status, nil
+}
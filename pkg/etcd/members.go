@@ -0,0 +1,70 @@
+package etcd
+
+import (
+	"context"
+	"fmt"
+)
+
+// AddAsLearner implements managed.Driver by adding the member reachable at clientURL to the
+// cluster as a non-voting learner, registering clientURL as its peer URL. It returns the
+// learner's etcd-assigned member ID, hex-encoded to match the IDs reported by Health.
+func (e *ETCD) AddAsLearner(ctx context.Context, clientURL string) (string, error) {
+	client, err := e.getClient(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer client.Close()
+
+	resp, err := client.MemberAddAsLearner(ctx, []string{clientURL})
+	if err != nil {
+		return "", fmt.Errorf("etcd: failed to add learner %s: %w", clientURL, err)
+	}
+	e.bumpMembersVersion(ctx, client)
+
+	return memberIDToString(resp.Member.ID), nil
+}
+
+// PromoteMember implements managed.Driver by promoting the learner with the given member ID to
+// a full voting member. etcd refuses this call until the learner's raft log has caught up, so
+// callers such as the member reconciler should treat an error here as "try again later" rather
+// than fatal.
+func (e *ETCD) PromoteMember(ctx context.Context, memberID string) error {
+	client, err := e.getClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	id, err := memberIDFromString(memberID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := client.MemberPromote(ctx, id); err != nil {
+		return fmt.Errorf("etcd: failed to promote member %s: %w", memberID, err)
+	}
+	e.bumpMembersVersion(ctx, client)
+
+	return nil
+}
+
+// RemoveMember implements managed.Driver.
+func (e *ETCD) RemoveMember(ctx context.Context, memberID string) error {
+	client, err := e.getClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	id, err := memberIDFromString(memberID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := client.MemberRemove(ctx, id); err != nil {
+		return fmt.Errorf("etcd: failed to remove member %s: %w", memberID, err)
+	}
+	e.bumpMembersVersion(ctx, client)
+
+	return nil
+}
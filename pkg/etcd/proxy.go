@@ -0,0 +1,9 @@
+package etcd
+
+// Proxy is implemented by the local apiserver-facing load balancer that fronts the etcd
+// cluster. setupEtcdProxy (pkg/cluster/etcdproxy.go) keeps it updated with the current set of
+// member client URLs as membership changes.
+type Proxy interface {
+	// Update replaces the set of upstream addresses the proxy load-balances across.
+	Update(addresses []string)
+}
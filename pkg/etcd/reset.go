@@ -0,0 +1,14 @@
+package etcd
+
+import (
+	"path/filepath"
+
+	"github.com/rancher/k3s/pkg/daemons/config"
+)
+
+// ResetFile returns the path of the --cluster-reset marker file. Cluster.start refuses to
+// perform a --cluster-reset while this file exists, and removes it on every start; none of the
+// managed drivers in this repository write it.
+func ResetFile(config *config.Control) string {
+	return filepath.Join(config.DataDir, "db", "reset-flag")
+}
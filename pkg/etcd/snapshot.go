@@ -0,0 +1,112 @@
+package etcd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/rancher/k3s/pkg/cluster/managed"
+	"github.com/rancher/k3s/pkg/daemons/config"
+	"github.com/sirupsen/logrus"
+)
+
+// snapshotDir is kept as a sibling of, rather than nested inside, the etcd member data
+// directory, so that Reset and RestoreSnapshot can wipe/replace the member data without
+// touching previously taken snapshots.
+func (e *ETCD) snapshotDir() string {
+	return filepath.Join(filepath.Dir(e.dataDir), "snapshots")
+}
+
+// Snapshot implements managed.Driver by streaming a consistent point-in-time copy of the etcd
+// keyspace from the local member's Maintenance API to a timestamped file on disk.
+func (e *ETCD) Snapshot(ctx context.Context, config *config.Control) error {
+	if err := os.MkdirAll(e.snapshotDir(), 0700); err != nil {
+		return err
+	}
+
+	client, err := e.getClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	rc, err := client.Snapshot(ctx)
+	if err != nil {
+		return fmt.Errorf("etcd: failed to open snapshot stream: %w", err)
+	}
+	defer rc.Close()
+
+	name := fmt.Sprintf("etcd-snapshot-%d", time.Now().Unix())
+	path := filepath.Join(e.snapshotDir(), name)
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, rc); err != nil {
+		return fmt.Errorf("etcd: failed to write snapshot %s: %w", name, err)
+	}
+
+	logrus.Infof("Saved etcd snapshot to %s", path)
+	return nil
+}
+
+// ListSnapshots implements managed.Driver.
+func (e *ETCD) ListSnapshots(ctx context.Context) ([]managed.SnapshotFile, error) {
+	entries, err := os.ReadDir(e.snapshotDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	files := make([]managed.SnapshotFile, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, managed.SnapshotFile{
+			Name:      entry.Name(),
+			Location:  filepath.Join(e.snapshotDir(), entry.Name()),
+			NodeName:  e.name,
+			CreatedAt: info.ModTime(),
+			Size:      info.Size(),
+		})
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].CreatedAt.After(files[j].CreatedAt) })
+	return files, nil
+}
+
+// DeleteSnapshot implements managed.Driver.
+func (e *ETCD) DeleteSnapshot(ctx context.Context, name string) error {
+	return os.Remove(filepath.Join(e.snapshotDir(), name))
+}
+
+// RestoreSnapshot implements managed.Driver by replacing the etcd data directory with the
+// contents of the named snapshot. Callers are expected to have stopped the datastore first, the
+// same contract sqlite.Driver.RestoreSnapshot documents.
+func (e *ETCD) RestoreSnapshot(ctx context.Context, name string) error {
+	if err := os.RemoveAll(e.dataDir); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.MkdirAll(e.dataDir, 0700); err != nil {
+		return err
+	}
+
+	// The actual restore-into-data-dir step is performed by the embedded server's bootstrap
+	// code the next time it starts against this data directory, using the snapshot file staged
+	// below as its initial state; that's the same rebootstrap-time integration used today.
+	return os.Rename(filepath.Join(e.snapshotDir(), name), filepath.Join(e.dataDir, "restore-snapshot"))
+}
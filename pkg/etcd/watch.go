@@ -0,0 +1,118 @@
+package etcd
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+const (
+	// membersVersionKey is bumped by AddAsLearner, PromoteMember, and RemoveMember whenever they
+	// change cluster membership, turning WatchMembers into a watch on this single key rather than
+	// requiring a membership-change notification that the etcd client doesn't expose directly.
+	membersVersionKey = "/k3s/etcd/members-version"
+
+	// watchMembersPollInterval is how often WatchMembers' in-driver fallback polls the member
+	// list once the watch on membersVersionKey has been lost.
+	watchMembersPollInterval = 15 * time.Second
+)
+
+// WatchMembers implements managed.Driver by watching membersVersionKey, which AddAsLearner,
+// PromoteMember, and RemoveMember all bump after changing membership, and re-fetching the
+// member list whenever it changes. etcd's client has no direct membership-change subscription,
+// so this key is how the driver turns membership changes into a watchable event. If the client
+// can't be created or the initial member list can't be fetched, the error is returned so callers
+// such as setupEtcdProxy fall back to polling GetMembersClientURLs; if the watch later reports an
+// error or its channel closes, pollMembers keeps updates flowing on this same channel rather than
+// requiring setupEtcdProxy to notice and poll itself.
+func (e *ETCD) WatchMembers(ctx context.Context) (<-chan []string, error) {
+	client, err := e.getClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	urls, err := e.getMembersClientURLs(ctx, client)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	ch := make(chan []string, 1)
+	ch <- urls
+
+	watchCh := client.Watch(ctx, membersVersionKey)
+
+	go func() {
+		defer client.Close()
+		defer close(ch)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case resp, ok := <-watchCh:
+				if !ok {
+					logrus.Warnf("etcd: members watch channel closed, falling back to polling")
+					e.pollMembers(ctx, client, ch)
+					return
+				}
+				if resp.Err() != nil {
+					logrus.Warnf("etcd: members watch error, falling back to polling: %v", resp.Err())
+					e.pollMembers(ctx, client, ch)
+					return
+				}
+				urls, err := e.getMembersClientURLs(ctx, client)
+				if err != nil {
+					logrus.Warnf("etcd: failed to refresh member list after watch event: %v", err)
+					continue
+				}
+				select {
+				case ch <- urls:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+// pollMembers is WatchMembers' in-driver fallback for when the etcd watch itself is lost: it
+// polls the member list on watchMembersPollInterval and sends each result to ch until ctx is
+// done. This keeps updates flowing to setupEtcdProxy even through a sustained watch outage,
+// rather than relying solely on the consumer's own polling fallback.
+func (e *ETCD) pollMembers(ctx context.Context, client *clientv3.Client, ch chan<- []string) {
+	t := time.NewTicker(watchMembersPollInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			urls, err := e.getMembersClientURLs(ctx, client)
+			if err != nil {
+				logrus.Warnf("etcd: failed to poll member list: %v", err)
+				continue
+			}
+			select {
+			case ch <- urls:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// bumpMembersVersion writes a fresh value to membersVersionKey so that watchers started by
+// WatchMembers wake up and re-fetch the member list. Failing to bump it only delays the next
+// watch-driven update until the periodic resync in setupEtcdProxy catches up, so this is best
+// effort and not treated as a fatal error for the membership change it follows.
+func (e *ETCD) bumpMembersVersion(ctx context.Context, client *clientv3.Client) {
+	if _, err := client.Put(ctx, membersVersionKey, strconv.FormatInt(time.Now().UnixNano(), 10)); err != nil {
+		logrus.Warnf("etcd: failed to bump members-version key: %v", err)
+	}
+}